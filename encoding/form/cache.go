@@ -0,0 +1,87 @@
+package form
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// fieldDescriptor is the precomputed, per-field metadata used on the
+// Unmarshal hot path, so that tag parsing and method lookups only happen
+// once per struct type rather than once per incoming key.
+type fieldDescriptor struct {
+	tag       *Tag
+	name      string // the Go field name, used to build ValidationError.Path
+	index     []int  // passed to reflect.Value.FieldByIndex
+	validator *reflect.Method
+	setter    *reflect.Method
+	getter    *reflect.Method
+}
+
+// structDescriptor is the cached, precomputed layout of a struct type's
+// 'form' tags.
+type structDescriptor struct {
+	fields []*fieldDescriptor          // every field (untagged ones named after the Go field), in declaration order
+	byName map[string]*fieldDescriptor // direct (non-group, non-ignored) fields, by tag name
+	groups []*fieldDescriptor          // group ('+') fields, most specific prefix/suffix first
+}
+
+var descriptorCache sync.Map // reflect.Type -> *structDescriptor
+
+// describeStruct returns the cached descriptor for rt, building and
+// caching it on first use.
+func describeStruct(rt reflect.Type) *structDescriptor {
+	if d, ok := descriptorCache.Load(rt); ok {
+		return d.(*structDescriptor)
+	}
+
+	ptrt := reflect.PtrTo(rt)
+	d := &structDescriptor{byName: map[string]*fieldDescriptor{}}
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := parseTag(f.Tag.Get("form"))
+		if !tag.Ignore && tag.Name == "" {
+			tag.Name = f.Name
+		}
+
+		fd := &fieldDescriptor{tag: tag, name: f.Name, index: f.Index}
+		d.fields = append(d.fields, fd)
+
+		if tag.Ignore {
+			continue
+		}
+
+		if m, ok := ptrt.MethodByName("FormValidate" + f.Name); ok {
+			fd.validator = &m
+		}
+		if m, ok := ptrt.MethodByName("FormSet" + f.Name); ok {
+			fd.setter = &m
+		}
+		if m, ok := ptrt.MethodByName("FormGet" + f.Name); ok {
+			fd.getter = &m
+		}
+
+		if tag.Group {
+			d.groups = append(d.groups, fd)
+			continue
+		}
+
+		// First match wins, matching the original linear scan in
+		// assignToStruct: if two fields share a tag name, the earlier one
+		// is used and later duplicates are ignored.
+		if _, ok := d.byName[tag.Name]; !ok {
+			d.byName[tag.Name] = fd
+		}
+	}
+
+	// Most specific prefix/suffix first, so an unprefixed group doesn't
+	// shadow a more specific one (e.g. `prefix=mail_`).
+	sort.SliceStable(d.groups, func(i, j int) bool {
+		ti, tj := d.groups[i].tag, d.groups[j].tag
+		return len(ti.Prefix)+len(ti.Suffix) > len(tj.Prefix)+len(tj.Suffix)
+	})
+
+	actual, _ := descriptorCache.LoadOrStore(rt, d)
+	return actual.(*structDescriptor)
+}
@@ -0,0 +1,96 @@
+package form
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// BenchStruct has ~20 fields, representative of a typical hot-path form.
+type BenchStruct struct {
+	Field1  string `form:"field1"`
+	Field2  string `form:"field2"`
+	Field3  string `form:"field3"`
+	Field4  string `form:"field4"`
+	Field5  string `form:"field5"`
+	Field6  int    `form:"field6"`
+	Field7  int    `form:"field7"`
+	Field8  int    `form:"field8"`
+	Field9  int    `form:"field9"`
+	Field10 int    `form:"field10"`
+	Field11 bool   `form:"field11"`
+	Field12 bool   `form:"field12"`
+	Field13 string `form:"field13,required"`
+	Field14 string `form:"field14,required"`
+	Field15 string `form:"field15"`
+	Field16 string `form:"field16"`
+	Field17 string `form:"field17"`
+	Field18 string `form:"field18"`
+	Field19 string `form:"field19"`
+	Field20 string `form:"field20"`
+}
+
+func benchValues() url.Values {
+	v := url.Values{}
+	v.Set("field1", "a")
+	v.Set("field2", "b")
+	v.Set("field3", "c")
+	v.Set("field4", "d")
+	v.Set("field5", "e")
+	v.Set("field6", "1")
+	v.Set("field7", "2")
+	v.Set("field8", "3")
+	v.Set("field9", "4")
+	v.Set("field10", "5")
+	v.Set("field11", "true")
+	v.Set("field12", "false")
+	v.Set("field13", "x")
+	v.Set("field14", "y")
+	v.Set("field15", "f")
+	v.Set("field16", "g")
+	v.Set("field17", "h")
+	v.Set("field18", "i")
+	v.Set("field19", "j")
+	v.Set("field20", "k")
+	return v
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	v := benchValues()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bs := &BenchStruct{}
+		if err := Unmarshal(v, bs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDescribeStructIsCached(t *testing.T) {
+	rt := reflect.TypeOf(BenchStruct{})
+	d1 := describeStruct(rt)
+	d2 := describeStruct(rt)
+	if d1 != d2 {
+		t.Error("Expected describeStruct to return the same cached descriptor on repeat calls")
+	}
+	if len(d1.fields) != 20 {
+		t.Errorf("Expected 20 fields, got %d", len(d1.fields))
+	}
+}
+
+func TestUnmarshalLargeStruct(t *testing.T) {
+	v := benchValues()
+	bs := &BenchStruct{}
+	if err := Unmarshal(v, bs); err != nil {
+		t.Fatal(err)
+	}
+	if bs.Field1 != "a" || bs.Field20 != "k" {
+		t.Errorf("Unexpected values: Field1=%q Field20=%q", bs.Field1, bs.Field20)
+	}
+	if bs.Field6 != 1 || bs.Field10 != 5 {
+		t.Errorf("Unexpected int values: Field6=%d Field10=%d", bs.Field6, bs.Field10)
+	}
+	if !bs.Field11 || bs.Field12 {
+		t.Errorf("Unexpected bool values: Field11=%v Field12=%v", bs.Field11, bs.Field12)
+	}
+}
@@ -0,0 +1,140 @@
+package form
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Unmarshaler is implemented by types that need to decode themselves from
+// a field's raw form values. Unlike encoding.TextUnmarshaler, it receives
+// every value submitted for the field, not just the first.
+type Unmarshaler interface {
+	UnmarshalForm(value []string) error
+}
+
+// DecoderFunc decodes a field's raw values into a value assignable to the
+// type it was registered for.
+type DecoderFunc func(value []string) (interface{}, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[reflect.Type]DecoderFunc{}
+)
+
+// RegisterDecoder registers a decoder for t (typically called once at
+// init), so that any field of that type, or a pointer to it, is decoded
+// with fn instead of the built-in kind-based assignment. This is the way
+// to teach Unmarshal about application types such as net.IP or uuid.UUID.
+func RegisterDecoder(t reflect.Type, fn DecoderFunc) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[t] = fn
+}
+
+func lookupDecoder(t reflect.Type) (DecoderFunc, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	fn, ok := decoders[t]
+	return fn, ok
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	formUnmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// decodeField assigns val to rv, trying in order: a nil-pointer allocation,
+// the built-in time.Time decoder, a decoder registered with
+// RegisterDecoder, encoding.TextUnmarshaler, form.Unmarshaler, per-element
+// decoding for slices other than []string, and finally the primitive-kind
+// assignment assignToStructField has always done. tag
+// may be nil; only its Layout option is consulted, for time.Time fields.
+func decodeField(rv reflect.Value, tag *Tag, val []string) error {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			if tag != nil && tag.Omit && empty(val) {
+				// Leave an omitempty pointer nil rather than allocating a
+				// zero value, so an empty submitted value (key present, no
+				// value) is distinguishable from the key being omitted.
+				// Pointer fields without omitempty are always allocated.
+				return nil
+			}
+			if !rv.CanSet() {
+				return fmt.Errorf("cannot allocate unexported field of type %s", rv.Type())
+			}
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeField(rv.Elem(), tag, val)
+	}
+
+	if rv.Type() == timeType {
+		return decodeTime(rv, tag, val)
+	}
+
+	if fn, ok := lookupDecoder(rv.Type()); ok {
+		v, err := fn(val)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	if rv.CanAddr() {
+		addr := rv.Addr()
+		if addr.Type().Implements(textUnmarshalerType) {
+			if empty(val) {
+				return nil
+			}
+			return addr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val[0]))
+		}
+		if addr.Type().Implements(formUnmarshalerType) {
+			return addr.Interface().(Unmarshaler).UnmarshalForm(val)
+		}
+	}
+
+	if rv.Kind() == reflect.Slice && rv.Type() != reflect.TypeOf([]string(nil)) {
+		return decodeSlice(rv, val)
+	}
+
+	return assignToStructField(rv, val)
+}
+
+// decodeTime decodes a time.Time field, using tag's `layout=` option if
+// present, and defaulting to time.RFC3339 otherwise.
+func decodeTime(rv reflect.Value, tag *Tag, val []string) error {
+	if empty(val) {
+		return nil
+	}
+	layout := time.RFC3339
+	if tag != nil && tag.Layout != "" {
+		layout = tag.Layout
+	}
+	t, err := time.Parse(layout, val[0])
+	if err != nil {
+		return err
+	}
+	rv.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// decodeSlice decodes each value in val into an element of a new slice of
+// rv's element type. []string fields are handled directly by
+// assignToStructField and never reach here.
+func decodeSlice(rv reflect.Value, val []string) error {
+	et := rv.Type().Elem()
+	out := reflect.MakeSlice(rv.Type(), len(val), len(val))
+	for i, s := range val {
+		ev := reflect.New(et).Elem()
+		if err := decodeField(ev, nil, []string{s}); err != nil {
+			return fmt.Errorf("element %d: %s", i, err)
+		}
+		out.Index(i).Set(ev)
+	}
+	rv.Set(out)
+	return nil
+}
@@ -0,0 +1,189 @@
+package form
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type EventForm struct {
+	Name     string     `form:"name"`
+	StartsAt time.Time  `form:"starts_at"`
+	EndsAt   *time.Time `form:"ends_at,omitempty"`
+	Birthday time.Time  `form:"birthday,layout=2006-01-02"`
+	Scores   []int      `form:"scores"`
+	Enabled  []bool     `form:"enabled"`
+	NilCount *int       `form:"nil_count,omitempty"`
+	Count    *int       `form:"count"`
+}
+
+func TestUnmarshalTime(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "Launch")
+	v.Set("starts_at", "2024-01-02T15:04:05Z")
+	v.Set("ends_at", "2024-01-02T16:04:05Z")
+	v.Set("birthday", "1990-05-17")
+
+	ef := &EventForm{}
+	if err := Unmarshal(v, ef); err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := time.Parse(time.RFC3339, "2024-01-02T15:04:05Z")
+	if !ef.StartsAt.Equal(want) {
+		t.Errorf("Expected StartsAt=%s, got %s", want, ef.StartsAt)
+	}
+
+	if ef.EndsAt == nil {
+		t.Fatal("Expected EndsAt to be allocated")
+	}
+	wantEnds, _ := time.Parse(time.RFC3339, "2024-01-02T16:04:05Z")
+	if !ef.EndsAt.Equal(wantEnds) {
+		t.Errorf("Expected EndsAt=%s, got %s", wantEnds, ef.EndsAt)
+	}
+
+	wantBday, _ := time.Parse("2006-01-02", "1990-05-17")
+	if !ef.Birthday.Equal(wantBday) {
+		t.Errorf("Expected Birthday=%s, got %s", wantBday, ef.Birthday)
+	}
+}
+
+func TestUnmarshalTimeInvalidValue(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "Launch")
+	v.Set("starts_at", "not-a-time")
+
+	ef := &EventForm{}
+	if err := Unmarshal(v, ef); err == nil {
+		t.Fatal("Expected an error for an invalid starts_at value")
+	}
+}
+
+func TestUnmarshalOmitemptyPointerLeftNilOnEmptyValue(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "Launch")
+	v.Set("starts_at", "2024-01-02T15:04:05Z")
+	v.Set("nil_count", "")
+
+	ef := &EventForm{}
+	if err := Unmarshal(v, ef); err != nil {
+		t.Fatal(err)
+	}
+	if ef.NilCount != nil {
+		t.Errorf("Expected NilCount to stay nil for an empty value, got %v", *ef.NilCount)
+	}
+}
+
+func TestUnmarshalNonOmitemptyPointerAllocatedOnEmptyValue(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "Launch")
+	v.Set("starts_at", "2024-01-02T15:04:05Z")
+	v.Set("count", "")
+
+	ef := &EventForm{}
+	if err := Unmarshal(v, ef); err != nil {
+		t.Fatal(err)
+	}
+	if ef.Count == nil {
+		t.Fatal("Expected Count to be allocated even for an empty value, since it has no omitempty tag")
+	}
+	if *ef.Count != 0 {
+		t.Errorf("Expected *Count=0, got %d", *ef.Count)
+	}
+}
+
+func TestUnmarshalNumericSlice(t *testing.T) {
+	v := url.Values{}
+	v["scores"] = []string{"1", "2", "3"}
+	v["enabled"] = []string{"true", "false"}
+
+	ef := &EventForm{}
+	if err := Unmarshal(v, ef); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(ef.Scores, []int{1, 2, 3}) {
+		t.Errorf("Expected Scores=[1 2 3], got %v", ef.Scores)
+	}
+	if !reflect.DeepEqual(ef.Enabled, []bool{true, false}) {
+		t.Errorf("Expected Enabled=[true false], got %v", ef.Enabled)
+	}
+}
+
+type Hex string
+
+func (h *Hex) UnmarshalText(text []byte) error {
+	*h = Hex("#" + string(text))
+	return nil
+}
+
+type MultiValue struct {
+	Parts []string
+}
+
+func (m *MultiValue) UnmarshalForm(value []string) error {
+	m.Parts = value
+	return nil
+}
+
+type CustomForm struct {
+	Color Hex        `form:"color"`
+	Multi MultiValue `form:"multi"`
+}
+
+func TestUnmarshalTextUnmarshaler(t *testing.T) {
+	v := url.Values{}
+	v.Set("color", "ff0000")
+
+	cf := &CustomForm{}
+	if err := Unmarshal(v, cf); err != nil {
+		t.Fatal(err)
+	}
+	if cf.Color != "#ff0000" {
+		t.Errorf("Expected Color=#ff0000, got %q", cf.Color)
+	}
+}
+
+func TestUnmarshalFormUnmarshaler(t *testing.T) {
+	v := url.Values{}
+	v["multi"] = []string{"a", "b", "c"}
+
+	cf := &CustomForm{}
+	if err := Unmarshal(v, cf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cf.Multi.Parts, []string{"a", "b", "c"}) {
+		t.Errorf("Expected Multi.Parts=[a b c], got %v", cf.Multi.Parts)
+	}
+}
+
+type Point struct {
+	X, Y int
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	RegisterDecoder(reflect.TypeOf(Point{}), func(value []string) (interface{}, error) {
+		var x, y int
+		if _, err := fmt.Sscanf(value[0], "%d,%d", &x, &y); err != nil {
+			return nil, err
+		}
+		return Point{X: x, Y: y}, nil
+	})
+
+	type Shape struct {
+		Origin Point `form:"origin"`
+	}
+
+	v := url.Values{}
+	v.Set("origin", "3,4")
+
+	s := &Shape{}
+	if err := Unmarshal(v, s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Origin != (Point{X: 3, Y: 4}) {
+		t.Errorf("Expected Origin={3 4}, got %+v", s.Origin)
+	}
+}
@@ -0,0 +1,35 @@
+package form
+
+import (
+	"encoding"
+	"reflect"
+	"sync"
+)
+
+// EncoderFunc encodes a value of the type it was registered for into its
+// raw form representation. It is the inverse of DecoderFunc.
+type EncoderFunc func(value interface{}) ([]string, error)
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[reflect.Type]EncoderFunc{}
+)
+
+// RegisterEncoder registers an encoder for t (typically called once at
+// init), so that any field of that type, or a pointer to it, is encoded
+// with fn instead of the built-in kind-based conversion. This is the
+// Marshal-side counterpart to RegisterDecoder.
+func RegisterEncoder(t reflect.Type, fn EncoderFunc) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[t] = fn
+}
+
+func lookupEncoder(t reflect.Type) (EncoderFunc, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	fn, ok := encoders[t]
+	return fn, ok
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
@@ -1,11 +1,13 @@
 package form
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -15,6 +17,17 @@ var LogDebug = false
 // ValidationError indicates that a field was not valid.
 type ValidationError struct {
 	Field, Message string
+
+	// Path is the Go field path to the offending field, e.g.
+	// ["MailingAddress", "Zip"] for a field nested inside a `+` group.
+	// It is populated by Unmarshal and is empty for ValidationErrors
+	// constructed directly by callers.
+	Path []string
+
+	// Name is the dotted tag-name path for the offending key, e.g.
+	// "mail_zip" or "home.street". Like Path, it is populated by
+	// Unmarshal.
+	Name string
 }
 
 // Invalid constructs a ValidationError.
@@ -49,6 +62,32 @@ func (c CompoundValidationError) Error() string {
 	return fmt.Sprintf("validation errors occurred: %s", strings.Join(msg, "; "))
 }
 
+// jsonValidationError is the wire shape of a single ValidationError within
+// a CompoundValidationError's MarshalJSON output.
+type jsonValidationError struct {
+	Field   string `json:"field"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON renders c as {"errors":[{"field":...,"path":...,"message":...}]},
+// suitable for serializing directly into an HTTP error response.
+func (c CompoundValidationError) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Errors []jsonValidationError `json:"errors"`
+	}{
+		Errors: make([]jsonValidationError, len(c.errs)),
+	}
+	for i, e := range c.errs {
+		out.Errors[i] = jsonValidationError{
+			Field:   e.Name,
+			Path:    strings.Join(e.Path, "."),
+			Message: e.Message,
+		}
+	}
+	return json.Marshal(out)
+}
+
 // Validator indicates whether a form field is valid.
 type Validator func(value []string) *ValidationError
 
@@ -99,50 +138,132 @@ func Tags(o interface{}) []*Tag {
 	if tt.Kind() != reflect.Struct {
 		return []*Tag{}
 	}
-	tags := []*Tag{}
 
-	// Look for a Field on struct that matches the key name.
-	for i := 0; i < tt.NumField(); i++ {
-		f := tt.Field(i)
-		tag := parseTag(f.Tag.Get("form"))
-		if !tag.Ignore && tag.Name == "" {
-			tag.Name = f.Name
-		}
-		tags = append(tags, tag)
+	desc := describeStruct(tt)
+	tags := make([]*Tag, len(desc.fields))
+	for i, fd := range desc.fields {
+		tags[i] = fd.tag
 	}
 	return tags
 }
 
+// keySegmentRE splits a form key into path segments, treating `[` and `]`
+// as delimiters. A plain key such as "street" yields a single segment; a
+// bracketed key such as "home[street]" or "contacts[0][name]" yields
+// ["home", "street"] or ["contacts", "0", "name"].
+var keySegmentRE = regexp.MustCompile(`[^\[\]]+`)
+
+func splitKey(key string) []string {
+	return keySegmentRE.FindAllString(key, -1)
+}
+
 func walk(val reflect.Value, v url.Values) error {
 	// Loop through values, top-down specificity
 	verrs := []*ValidationError{}
 	for key, vals := range v {
-		e := findIn(val, key, vals)
+		path := splitKey(key)
+		if len(path) == 0 {
+			continue
+		}
+		e := findIn(val, path, vals, nil)
 		if e == nil {
 			continue
+		} else if e == errFieldNotFound {
+			fmt.Printf("Skipped key %q", key)
+			continue
 		} else if ve, ok := e.(*ValidationError); ok {
+			ve.Name = strings.Join(path, ".")
 			verrs = append(verrs, ve)
 			continue
+		} else if cve, ok := e.(CompoundValidationError); ok {
+			name := strings.Join(path, ".")
+			for _, ve := range cve.errs {
+				ve.Name = name
+			}
+			verrs = append(verrs, cve.errs...)
+			continue
 		}
 		return e
 	}
+
+	// Tag validators (e.g. `required`) only ran above for keys actually
+	// submitted. Sweep the struct (including nested `+` groups) for
+	// validator-bearing fields whose key never appeared in v at all, and
+	// validate those against a nil value too, so `required` catches an
+	// omitted field the same way it catches one submitted empty.
+	if rt := reflect.Indirect(val).Type(); rt.Kind() == reflect.Struct {
+		verrs = append(verrs, sweepMissing(rt, v, "", "", nil, nil)...)
+	}
+
 	if len(verrs) > 0 {
 		return CompoundValidationError{errs: verrs}
 	}
 	return nil
 }
 
-func findIn(rv reflect.Value, key string, values []string) error {
+// sweepMissing finds fields of rt (and, recursively, its `+` group fields)
+// whose tag carries validator specs but whose key never appears in v, and
+// runs those validators against a nil value. visiting guards against
+// self-referential or mutually-recursive group types (e.g. a linked list)
+// recursing forever; it is the list of struct types already being swept
+// along the current descent path.
+func sweepMissing(rt reflect.Type, v url.Values, prefix, suffix string, fieldPath []string, visiting []reflect.Type) []*ValidationError {
+	for _, t := range visiting {
+		if t == rt {
+			return nil
+		}
+	}
+	visiting = append(append([]reflect.Type{}, visiting...), rt)
+
+	desc := describeStruct(rt)
+	var verrs []*ValidationError
+
+	for _, fd := range desc.fields {
+		if fd.tag.Ignore || fd.tag.Group || len(fd.tag.Validators) == 0 {
+			continue
+		}
+		key := prefix + fd.tag.Name + suffix
+		if _, present := v[key]; present {
+			continue
+		}
+		errs := runValidators(key, fd.tag.Validators, nil)
+		if len(errs) == 0 {
+			continue
+		}
+		path := appendPath(fieldPath, fd.name)
+		name := strings.Join(splitKey(key), ".")
+		for _, e := range errs {
+			e.Path = path
+			e.Name = name
+		}
+		verrs = append(verrs, errs...)
+	}
+
+	for _, fd := range desc.groups {
+		gt := rt.FieldByIndex(fd.index).Type
+		if gt.Kind() == reflect.Ptr {
+			gt = gt.Elem()
+		}
+		if gt.Kind() != reflect.Struct {
+			continue
+		}
+		verrs = append(verrs, sweepMissing(gt, v, prefix+fd.tag.Prefix, fd.tag.Suffix+suffix, appendPath(fieldPath, fd.name), visiting)...)
+	}
+
+	return verrs
+}
+
+func findIn(rv reflect.Value, path []string, values []string, fieldPath []string) error {
 	switch reflect.Indirect(rv).Kind() {
 	case reflect.Map:
 		// The map must take string keys.
 		if _, ok := rv.Interface().(map[string]interface{}); ok {
-			return assignToMap(rv, key, values)
+			return assignToMap(rv, path[0], values)
 		}
 	case reflect.Struct:
-		// Look for struct field named 'key'.
-		//return assignToStruct(reflect.Indirect(rv), key, values)
-		return assignToStruct(rv, key, values)
+		// Look for struct field named 'key', descending into path[1:] if
+		// the key uses bracket notation.
+		return assignToStructPath(rv, path, values, fieldPath)
 	}
 	return fmt.Errorf("object %s cannot be used to store values", rv.Type().Name())
 }
@@ -167,54 +288,215 @@ func assignToMap(rv reflect.Value, key string, values []string) error {
 	return err
 }
 
-func assignToStruct(rval reflect.Value, key string, values []string) error {
-	ptrt := rval.Type()
+// errFieldNotFound indicates that no struct field (direct or grouped)
+// matched a given key. It is not a fatal error: callers skip the key the
+// same way an unmatched field has always been skipped.
+var errFieldNotFound = errors.New("no matching field")
+
+// assignToStructPath resolves a (possibly bracketed) key path against rval,
+// descending into `+` group fields by stripping their prefix/suffix, and
+// into nested structs, slices, and maps for the remainder of a bracketed
+// path such as "contacts[0][name]".
+func assignToStructPath(rval reflect.Value, path []string, values []string, fieldPath []string) error {
+	if len(path) == 0 {
+		return errFieldNotFound
+	}
+	key := path[0]
+	rest := path[1:]
+
 	rv := reflect.Indirect(rval)
-	rt := rv.Type()
-	// Look for a Field on struct that matches the key name.
-	for i := 0; i < rt.NumField(); i++ {
-		f := rt.Field(i)
-		tag := parseTag(f.Tag.Get("form"))
-		if !tag.Ignore && tag.Name == "" {
-			tag.Name = f.Name
-		}
-		if tag.Name == key {
-			validator := "FormValidate" + f.Name
-			setter := "FormSet" + f.Name
-
-			// If there is a validator, call it.
-			if m, ok := ptrt.MethodByName(validator); ok {
-				if LogDebug {
-					log.Printf("Validating %s against %v\n", key, m)
-				}
-				if err := callFormMethod(m, rval, values); err != nil {
-					if LogDebug {
-						log.Printf("Validation of %s=%v failed: %s", key, values, err)
-					}
-					return err
-				}
+	desc := describeStruct(rv.Type())
+
+	// First, look for a direct (non-group) field match.
+	if fd, ok := desc.byName[key]; ok {
+		if len(rest) == 0 {
+			return assignField(rval, rv, fd, key, values, fieldPath)
+		}
+		return descendInto(rv.FieldByIndex(fd.index), rest, values, appendPath(fieldPath, fd.name))
+	}
+
+	// Next, try grouped fields, most specific prefix/suffix first, so that
+	// e.g. `prefix=mail_` doesn't get shadowed by an unprefixed group.
+	for _, fd := range desc.groups {
+		tag := fd.tag
+		if !strings.HasPrefix(key, tag.Prefix) || !strings.HasSuffix(key, tag.Suffix) {
+			continue
+		}
+		childKey := key[len(tag.Prefix) : len(key)-len(tag.Suffix)]
+		if childKey == "" {
+			continue
+		}
+
+		gv, err := allocateGroup(rv.FieldByIndex(fd.index))
+		if err != nil {
+			return err
+		}
+		err = assignToStructPath(gv, append([]string{childKey}, rest...), values, appendPath(fieldPath, fd.name))
+		if err == errFieldNotFound {
+			continue
+		}
+		return err
+	}
+
+	return errFieldNotFound
+}
+
+// appendPath returns a new slice with name appended to fieldPath, without
+// mutating fieldPath's backing array (callers may reuse it across sibling
+// group attempts).
+func appendPath(fieldPath []string, name string) []string {
+	out := make([]string, len(fieldPath), len(fieldPath)+1)
+	copy(out, fieldPath)
+	return append(out, name)
+}
+
+// allocateGroup returns an addressable pointer to fv, allocating fv if it
+// is a nil pointer.
+func allocateGroup(fv reflect.Value) (reflect.Value, error) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			if !fv.CanSet() {
+				return reflect.Value{}, fmt.Errorf("cannot allocate unexported field of type %s", fv.Type())
 			}
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return fv, nil
+	}
+	return fv.Addr(), nil
+}
 
-			// For assignment, if there is a setter, use it. Otherwise, do a
-			// raw assignment.
-			if m, ok := ptrt.MethodByName(setter); ok {
-				if LogDebug {
-					log.Printf("Setting %s with %v\n", key, m)
-				}
-				return callFormMethod(m, rval, values)
+// descendInto walks the remainder of a bracketed key path (e.g. the
+// ["0", "name"] left over from "contacts[0][name]") into a nested struct,
+// slice, or map field.
+func descendInto(fv reflect.Value, path []string, values []string, fieldPath []string) error {
+	switch fv.Kind() {
+	case reflect.Ptr:
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return descendInto(fv.Elem(), path, values, fieldPath)
+	case reflect.Struct:
+		if len(path) == 0 {
+			return errFieldNotFound
+		}
+		return assignToStructPath(fv.Addr(), path, values, fieldPath)
+	case reflect.Slice:
+		if len(path) == 0 {
+			return errFieldNotFound
+		}
+		idx, err := strconv.Atoi(path[0])
+		if err != nil {
+			return fmt.Errorf("expected a numeric index, got %q", path[0])
+		}
+		if idx < 0 {
+			return fmt.Errorf("expected a non-negative index, got %d", idx)
+		}
+		for fv.Len() <= idx {
+			fv.Set(reflect.Append(fv, reflect.Zero(fv.Type().Elem())))
+		}
+		return descendInto(fv.Index(idx), path[1:], values, fieldPath)
+	case reflect.Map:
+		if len(path) == 0 {
+			return errFieldNotFound
+		}
+		if fv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot descend into map with %s keys, only string keys are supported", fv.Type().Key().Kind())
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.MakeMap(fv.Type()))
+		}
+		mk := reflect.ValueOf(path[0]).Convert(fv.Type().Key())
+		ev := reflect.New(fv.Type().Elem()).Elem()
+		if existing := fv.MapIndex(mk); existing.IsValid() {
+			ev.Set(existing)
+		}
+		if err := descendInto(ev, path[1:], values, fieldPath); err != nil {
+			return err
+		}
+		fv.SetMapIndex(mk, ev)
+		return nil
+	default:
+		if len(path) != 0 {
+			return fmt.Errorf("cannot descend into %s", fv.Kind())
+		}
+		return decodeField(fv, nil, values)
+	}
+}
+
+func assignToStruct(rval reflect.Value, key string, values []string) error {
+	rv := reflect.Indirect(rval)
+	desc := describeStruct(rv.Type())
+
+	fd, ok := desc.byName[key]
+	if !ok {
+		fmt.Printf("Skipped key %q", key)
+		return nil
+	}
+	return assignField(rval, rv, fd, key, values, nil)
+}
+
+// assignField validates and assigns values to the field described by fd,
+// using rval (the pointer passed in by the caller) to invoke any
+// FormValidate/FormSet method hooks, and rv (its indirect struct value) to
+// reach the field itself. fieldPath is the Go field path of the struct(s)
+// traversed to reach fd, e.g. ["MailingAddress"] for a field inside a `+`
+// group; it is recorded on any *ValidationError produced here.
+func assignField(rval, rv reflect.Value, fd *fieldDescriptor, key string, values []string, fieldPath []string) error {
+	// Run any declarative validators parsed from the tag itself.
+	verrs := runValidators(key, fd.tag.Validators, values)
+
+	// If there is a validator method, call it too.
+	if fd.validator != nil {
+		if LogDebug {
+			log.Printf("Validating %s against %v\n", key, *fd.validator)
+		}
+		if err := callFormMethod(*fd.validator, rval, values); err != nil {
+			if LogDebug {
+				log.Printf("Validation of %s=%v failed: %s", key, values, err)
+			}
+			if ve, ok := err.(*ValidationError); ok {
+				verrs = append(verrs, ve)
 			} else {
-				if LogDebug {
-					log.Printf("Assigning %s value %v", key, values)
-				}
-				err := assignToStructField(rv.FieldByName(f.Name), values)
-				if LogDebug && err != nil {
-					log.Printf("Error assigning %s value %v: %s", key, values, err)
-				}
-				return nil
+				return err
 			}
 		}
 	}
-	fmt.Printf("Skipped key %q", key)
+
+	if len(verrs) > 0 {
+		path := appendPath(fieldPath, fd.name)
+		for _, ve := range verrs {
+			ve.Path = path
+		}
+	}
+
+	switch len(verrs) {
+	case 0:
+		// No failures; fall through to assignment.
+	case 1:
+		return verrs[0]
+	default:
+		return CompoundValidationError{errs: verrs}
+	}
+
+	// For assignment, if there is a setter, use it. Otherwise, do a raw
+	// assignment.
+	if fd.setter != nil {
+		if LogDebug {
+			log.Printf("Setting %s with %v\n", key, *fd.setter)
+		}
+		return callFormMethod(*fd.setter, rval, values)
+	}
+
+	if LogDebug {
+		log.Printf("Assigning %s value %v", key, values)
+	}
+	err := decodeField(rv.FieldByIndex(fd.index), fd.tag, values)
+	if err != nil {
+		if LogDebug {
+			log.Printf("Error assigning %s value %v: %s", key, values, err)
+		}
+		return fmt.Errorf("%s: %s", key, err)
+	}
 	return nil
 }
 
@@ -329,12 +611,22 @@ func parseTag(str string) *Tag {
 
 	for _, p := range parts[1:] {
 		switch {
+		case p == "":
+			// Ignore stray commas.
 		case p == "omitempty":
 			t.Omit = true
 		case strings.HasPrefix(p, "prefix="):
 			t.Prefix = strings.TrimPrefix(p, "prefix=")
 		case strings.HasPrefix(p, "suffix="):
 			t.Suffix = strings.TrimPrefix(p, "suffix=")
+		case strings.HasPrefix(p, "layout="):
+			t.Layout = strings.TrimPrefix(p, "layout=")
+		default:
+			name, param := p, ""
+			if i := strings.Index(p, "="); i >= 0 {
+				name, param = p[:i], p[i+1:]
+			}
+			t.Validators = append(t.Validators, ValidatorSpec{Name: name, Param: param})
 		}
 	}
 	return t
@@ -345,12 +637,15 @@ func parseTag(str string) *Tag {
 //	Name string `form:name`
 //	Date time.Time `form:date,omitempty`
 //	Address *Address `form:+,omitempty,prefix=addr_
+//	Age int `form:age,required,min=0,max=150`
 type Tag struct {
 	Name           string
-	Prefix, Suffix string //prefix=, suffix=
-	Omit           bool   // omitempty
-	Ignore         bool   // -
-	Group          bool   // +
+	Prefix, Suffix string          //prefix=, suffix=
+	Layout         string          // layout=2006-01-02, used for time.Time fields
+	Omit           bool            // omitempty
+	Ignore         bool            // -
+	Group          bool            // +
+	Validators     []ValidatorSpec // required, min=0, max=150, ...
 	validator      Validator
 	unmarshaler    FieldUnmarshaler
 }
@@ -265,17 +265,17 @@ type FixtureAddress struct {
 
 func (f *FixtureAddress) FormValidateState(vs []string) *ValidationError {
 	if len(vs) == 0 || vs[0] == "" {
-		return &ValidationError{"state", "state is required"}
+		return &ValidationError{Field: "state", Message: "state is required"}
 	}
 	if vs[0] != "Illinois" {
-		return &ValidationError{"state", "unknown state"}
+		return &ValidationError{Field: "state", Message: "unknown state"}
 	}
 	return nil
 }
 
 func (f *FixtureAddress) FormValidateStreet(vs []string) *ValidationError {
 	if vs[0] == "FAIL" {
-		return &ValidationError{"street", "street cannot be FAIL"}
+		return &ValidationError{Field: "street", Message: "street cannot be FAIL"}
 	}
 	return nil
 }
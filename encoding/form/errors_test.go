@@ -0,0 +1,101 @@
+package form
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+type ErrMailingAddress struct {
+	Zip int `form:"zip,required"`
+}
+
+func (a *ErrMailingAddress) FormValidateZip(vs []string) *ValidationError {
+	if vs[0] == "00000" {
+		return Invalid("zip", "zip cannot be all zeros")
+	}
+	return nil
+}
+
+type ErrContactForm struct {
+	Name    string             `form:"name,required"`
+	Mailing *ErrMailingAddress `form:"+,omitempty,prefix=mail_"`
+}
+
+func TestUnmarshalErrorPath(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "Matt")
+	v.Set("mail_zip", "00000")
+
+	cf := &ErrContactForm{}
+	err := Unmarshal(v, cf)
+	if err == nil {
+		t.Fatal("Expected validation failure")
+	}
+
+	cve, ok := err.(CompoundValidationError)
+	if !ok {
+		t.Fatalf("Expected CompoundValidationError, got %T", err)
+	}
+	if cve.Len() != 1 {
+		t.Fatalf("Expected 1 validation error, got %d: %s", cve.Len(), cve.Error())
+	}
+
+	ve := cve.Errors()[0]
+	if ve.Name != "mail_zip" {
+		t.Errorf("Expected Name=mail_zip, got %q", ve.Name)
+	}
+	wantPath := []string{"Mailing", "Zip"}
+	if len(ve.Path) != len(wantPath) {
+		t.Fatalf("Expected Path=%v, got %v", wantPath, ve.Path)
+	}
+	for i := range wantPath {
+		if ve.Path[i] != wantPath[i] {
+			t.Errorf("Expected Path=%v, got %v", wantPath, ve.Path)
+			break
+		}
+	}
+}
+
+func TestCompoundValidationErrorMarshalJSON(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "")
+	v.Set("mail_zip", "00000")
+
+	cf := &ErrContactForm{}
+	err := Unmarshal(v, cf)
+	cve, ok := err.(CompoundValidationError)
+	if !ok {
+		t.Fatalf("Expected CompoundValidationError, got %T", err)
+	}
+
+	b, err := json.Marshal(cve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Errors []struct {
+			Field   string `json:"field"`
+			Path    string `json:"path"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Expected valid JSON, got error: %s (payload: %s)", err, b)
+	}
+	if len(out.Errors) != 2 {
+		t.Fatalf("Expected 2 errors in JSON, got %d: %s", len(out.Errors), b)
+	}
+
+	byField := map[string]string{}
+	for _, e := range out.Errors {
+		byField[e.Field] = e.Path
+	}
+	if path, ok := byField["mail_zip"]; !ok || path != "Mailing.Zip" {
+		t.Errorf("Expected mail_zip path=Mailing.Zip, got %q (ok=%v)", path, ok)
+	}
+	if _, ok := byField["name"]; !ok {
+		t.Errorf("Expected an error for name, got %s", b)
+	}
+}
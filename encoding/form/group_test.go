@@ -0,0 +1,104 @@
+package form
+
+import (
+	"net/url"
+	"testing"
+)
+
+type GroupAddress struct {
+	Street string `form:"street"`
+	City   string `form:"city"`
+}
+
+type GroupForm struct {
+	FirstName string        `form:"first_name"`
+	Home      *GroupAddress `form:"+,omitempty"`
+	Mailing   *GroupAddress `form:"+,omitempty,prefix=mail_"`
+}
+
+func TestUnmarshalGroup(t *testing.T) {
+	v := url.Values{}
+	v.Set("first_name", "Matt")
+	v.Set("street", "1234 Tape Dr")
+	v.Set("mail_street", "4321 Disk Dr")
+
+	gf := &GroupForm{}
+	if err := Unmarshal(v, gf); err != nil {
+		t.Fatal(err)
+	}
+
+	if gf.FirstName != "Matt" {
+		t.Errorf("Expected FirstName=Matt, got %q", gf.FirstName)
+	}
+
+	if gf.Home == nil || gf.Home.Street != "1234 Tape Dr" {
+		t.Errorf("Expected Home.Street=1234 Tape Dr, got %+v", gf.Home)
+	}
+
+	if gf.Mailing == nil || gf.Mailing.Street != "4321 Disk Dr" {
+		t.Errorf("Expected Mailing.Street=4321 Disk Dr, got %+v", gf.Mailing)
+	}
+}
+
+type Contact struct {
+	Name string `form:"name"`
+}
+
+type BracketForm struct {
+	Home     GroupAddress      `form:"home"`
+	Contacts []Contact         `form:"contacts"`
+	Tags     map[string]string `form:"tags"`
+}
+
+func TestUnmarshalBracketNotation(t *testing.T) {
+	v := url.Values{}
+	v.Set("home[street]", "1234 Tape Dr")
+	v.Set("contacts[0][name]", "Alice")
+	v.Set("contacts[1][name]", "Bob")
+	v.Set("tags[color]", "blue")
+
+	bf := &BracketForm{}
+	if err := Unmarshal(v, bf); err != nil {
+		t.Fatal(err)
+	}
+
+	if bf.Home.Street != "1234 Tape Dr" {
+		t.Errorf("Expected Home.Street=1234 Tape Dr, got %q", bf.Home.Street)
+	}
+
+	if len(bf.Contacts) != 2 {
+		t.Fatalf("Expected 2 contacts, got %d", len(bf.Contacts))
+	}
+	if bf.Contacts[0].Name != "Alice" || bf.Contacts[1].Name != "Bob" {
+		t.Errorf("Unexpected contacts: %+v", bf.Contacts)
+	}
+
+	if bf.Tags["color"] != "blue" {
+		t.Errorf("Expected tags[color]=blue, got %q", bf.Tags["color"])
+	}
+}
+
+func TestSplitKey(t *testing.T) {
+	tests := []struct {
+		key    string
+		expect []string
+	}{
+		{"street", []string{"street"}},
+		{"home[street]", []string{"home", "street"}},
+		{"contacts[0][name]", []string{"contacts", "0", "name"}},
+	}
+
+	for _, tt := range tests {
+		got := splitKey(tt.key)
+		if len(got) != len(tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.key, tt.expect, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expect[i] {
+				t.Errorf("%s: expected %v, got %v", tt.key, tt.expect, got)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package form
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Marshal encodes the given struct into a url.Values, suitable for use with
+// http.Client.PostForm or similar.
+//
+// This is the inverse of Unmarshal, and understands the same tags:
+//
+//	type Person struct {
+//		First string `form:"first_name"`
+//		Last string `form:"last_name"`
+//	}
+//
+// Fields tagged with `-` are skipped. Fields tagged with `omitempty` are
+// skipped if they hold a zero value. Fields tagged with `+` are treated as
+// a group: Marshal descends into the (possibly pointer) struct and encodes
+// its fields into the same url.Values, applying `prefix=` and `suffix=` to
+// each of the group's keys.
+//
+// If a field has a matching FormGet method, that method is called to
+// produce the field's values instead of reading the field directly. For
+// example, given the First field above, the getter would be:
+//
+//	func(p *Person) FormGetFirst() ([]string, error) {}
+//
+// o must be a pointer to a struct.
+func Marshal(o interface{}) (url.Values, error) {
+	val := reflect.ValueOf(o)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return nil, errors.New("marshal requires a pointer to a receiver")
+	}
+
+	v := url.Values{}
+	if err := marshalStruct(val, v, "", ""); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func marshalStruct(rval reflect.Value, v url.Values, prefix, suffix string) error {
+	ptrt := rval.Type()
+	rv := reflect.Indirect(rval)
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := parseTag(f.Tag.Get("form"))
+		if tag.Ignore {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if tag.Group {
+			gv := fv
+			if gv.Kind() == reflect.Ptr {
+				if gv.IsNil() {
+					if tag.Omit {
+						continue
+					}
+					gv = reflect.New(gv.Type().Elem())
+				}
+			} else {
+				gv = fv.Addr()
+			}
+			if err := marshalStruct(gv, v, prefix+tag.Prefix, tag.Suffix+suffix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if tag.Name == "" {
+			tag.Name = f.Name
+		}
+
+		if tag.Omit && fv.IsZero() {
+			continue
+		}
+
+		getter := "FormGet" + f.Name
+		var vals []string
+		if m, ok := ptrt.MethodByName(getter); ok {
+			if LogDebug {
+				log.Printf("Getting %s with %v\n", tag.Name, m)
+			}
+			retvals := m.Func.Call([]reflect.Value{rval})
+			if !retvals[1].IsNil() {
+				return retvals[1].Interface().(error)
+			}
+			vals = retvals[0].Interface().([]string)
+		} else {
+			var err error
+			vals, err = marshalStructField(fv, tag)
+			if err != nil {
+				return err
+			}
+		}
+
+		v[prefix+tag.Name+suffix] = vals
+	}
+	return nil
+}
+
+// marshalStructField converts rv to its raw form representation, trying in
+// order: a nil-pointer short-circuit, the built-in time.Time encoder (which
+// honors tag's `layout=` option), an encoder registered with
+// RegisterEncoder, encoding.TextMarshaler, and finally a primitive-kind
+// conversion. tag may be nil.
+func marshalStructField(rv reflect.Value, tag *Tag) ([]string, error) {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return marshalStructField(rv.Elem(), tag)
+	}
+
+	if rv.Type() == timeType {
+		return marshalTime(rv, tag)
+	}
+
+	if fn, ok := lookupEncoder(rv.Type()); ok {
+		return fn(rv.Interface())
+	}
+
+	if tm, ok := textMarshalerFor(rv); ok {
+		b, err := tm.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(b)}, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return []string{rv.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []string{strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return []string{strconv.FormatFloat(rv.Float(), 'f', -1, 64)}, nil
+	case reflect.Bool:
+		return []string{strconv.FormatBool(rv.Bool())}, nil
+	case reflect.Slice:
+		if vs, ok := rv.Interface().([]string); ok {
+			return vs, nil
+		}
+		return nil, fmt.Errorf("only string slices are supported")
+	default:
+		return nil, fmt.Errorf("unsupported kind")
+	}
+}
+
+// marshalTime encodes a time.Time field, using tag's `layout=` option if
+// present, and defaulting to time.RFC3339 otherwise, mirroring decodeTime.
+func marshalTime(rv reflect.Value, tag *Tag) ([]string, error) {
+	layout := time.RFC3339
+	if tag != nil && tag.Layout != "" {
+		layout = tag.Layout
+	}
+	return []string{rv.Interface().(time.Time).Format(layout)}, nil
+}
+
+// textMarshalerFor returns rv as an encoding.TextMarshaler if its type
+// implements the interface, or else its address, if rv is addressable and
+// the pointer type implements it.
+func textMarshalerFor(rv reflect.Value) (encoding.TextMarshaler, bool) {
+	if rv.Type().Implements(textMarshalerType) {
+		return rv.Interface().(encoding.TextMarshaler), true
+	}
+	if rv.CanAddr() && rv.Addr().Type().Implements(textMarshalerType) {
+		return rv.Addr().Interface().(encoding.TextMarshaler), true
+	}
+	return nil, false
+}
@@ -0,0 +1,94 @@
+package form
+
+import (
+	"testing"
+	"time"
+)
+
+type MarshalAddress struct {
+	Street string `form:"street"`
+	City   string `form:"city"`
+}
+
+type MarshalTestStruct struct {
+	FirstName   string          `form:"first_name"`
+	LastName    string          `form:"last_name,omitempty"`
+	Home        *MarshalAddress `form:"+,omitempty"`
+	Mailing     *MarshalAddress `form:"+,omitempty,prefix=mail_"`
+	Processed   bool            `form:"-"`
+	Description string
+}
+
+func (m *MarshalTestStruct) FormGetDescription() ([]string, error) {
+	return []string{"custom:" + m.Description}, nil
+}
+
+func TestMarshal(t *testing.T) {
+	m := &MarshalTestStruct{
+		FirstName:   "Matt",
+		Home:        &MarshalAddress{Street: "1234 Tape Dr", City: "Glenview"},
+		Description: "hello",
+		Processed:   true,
+	}
+
+	v, err := Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := v.Get("first_name"); got != "Matt" {
+		t.Errorf("Expected first_name=Matt, got %q", got)
+	}
+
+	if _, ok := v["last_name"]; ok {
+		t.Errorf("Expected last_name to be omitted")
+	}
+
+	if got := v.Get("street"); got != "1234 Tape Dr" {
+		t.Errorf("Expected street=1234 Tape Dr, got %q", got)
+	}
+
+	if _, ok := v["mail_street"]; ok {
+		t.Errorf("Expected mailing address to be omitted")
+	}
+
+	if _, ok := v["Processed"]; ok {
+		t.Errorf("Expected Processed to be ignored")
+	}
+
+	if got := v.Get("Description"); got != "custom:hello" {
+		t.Errorf("Expected FormGetDescription to be used, got %q", got)
+	}
+}
+
+type MarshalEvent struct {
+	Name     string    `form:"name"`
+	StartsAt time.Time `form:"starts_at"`
+	Birthday time.Time `form:"birthday,layout=2006-01-02"`
+}
+
+func TestMarshalTime(t *testing.T) {
+	me := &MarshalEvent{
+		Name:     "Launch",
+		StartsAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Birthday: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+	}
+
+	v, err := Marshal(me)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := v.Get("starts_at"); got != "2024-01-02T15:04:05Z" {
+		t.Errorf("Expected starts_at=2024-01-02T15:04:05Z, got %q", got)
+	}
+	if got := v.Get("birthday"); got != "1990-05-17" {
+		t.Errorf("Expected birthday=1990-05-17, got %q", got)
+	}
+}
+
+func TestMarshalRequiresPointer(t *testing.T) {
+	if _, err := Marshal(MarshalTestStruct{}); err == nil {
+		t.Error("Expected an error when marshaling a non-pointer")
+	}
+}
@@ -0,0 +1,216 @@
+package form
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidatorSpec is a single validator directive parsed from a `form` tag,
+// e.g. `form:"age,required,min=0"` parses into
+// []ValidatorSpec{{Name: "required"}, {Name: "min", Param: "0"}}.
+type ValidatorSpec struct {
+	Name, Param string
+}
+
+// ValidatorFunc validates value, using param (the text after `=` in the
+// tag directive, if any) to parameterize the check. It returns nil if
+// value is valid.
+type ValidatorFunc func(value []string, param string) *ValidationError
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]ValidatorFunc{
+		"required": validateRequired,
+		"min":      validateMin,
+		"max":      validateMax,
+		"len":      validateLen,
+		"email":    validateEmail,
+		"url":      validateURL,
+		"regexp":   validateRegexp,
+		"oneof":    validateOneof,
+	}
+
+	aliasesMu sync.RWMutex
+	aliases   = map[string]string{}
+)
+
+// RegisterValidator registers a named validator for use in `form` tag
+// directives, e.g. `form:"age,required,min=0"`. Registering a name that
+// already exists (including a built-in) overwrites it.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[name] = fn
+}
+
+// RegisterAlias registers a name that expands to a `|`-separated list of
+// other validator names, e.g. RegisterAlias("iscolor", "hexcolor|rgb|rgba").
+// A tag directive referencing the alias passes if any one of the expanded
+// validators passes.
+func RegisterAlias(name, expr string) {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	aliases[name] = expr
+}
+
+func lookupValidator(name string) (ValidatorFunc, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	fn, ok := validators[name]
+	return fn, ok
+}
+
+func lookupAlias(name string) (string, bool) {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	expr, ok := aliases[name]
+	return expr, ok
+}
+
+// runValidators runs every validator directive in specs against value,
+// returning one ValidationError per failing directive.
+func runValidators(field string, specs []ValidatorSpec, value []string) []*ValidationError {
+	var errs []*ValidationError
+	for _, spec := range specs {
+		if expr, ok := lookupAlias(spec.Name); ok {
+			if err := runAlias(expr, value); err != nil {
+				err.Field = field
+				errs = append(errs, err)
+			}
+			continue
+		}
+		fn, ok := lookupValidator(spec.Name)
+		if !ok {
+			errs = append(errs, Invalid(field, "unknown validator %q", spec.Name))
+			continue
+		}
+		if err := fn(value, spec.Param); err != nil {
+			err.Field = field
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// runAlias runs each `|`-separated validator name in expr against value,
+// passing if any one of them passes.
+func runAlias(expr string, value []string) *ValidationError {
+	for _, name := range strings.Split(expr, "|") {
+		fn, ok := lookupValidator(name)
+		if !ok {
+			continue
+		}
+		if fn(value, "") == nil {
+			return nil
+		}
+	}
+	return Invalid("", "must satisfy one of: %s", expr)
+}
+
+func validateRequired(value []string, _ string) *ValidationError {
+	if empty(value) {
+		return Invalid("", "is required")
+	}
+	return nil
+}
+
+func validateMin(value []string, param string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	min, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return Invalid("", "invalid min parameter %q", param)
+	}
+	n, err := strconv.ParseFloat(value[0], 64)
+	if err != nil {
+		return Invalid("", "must be a number")
+	}
+	if n < min {
+		return Invalid("", "must be at least %s", param)
+	}
+	return nil
+}
+
+func validateMax(value []string, param string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	max, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return Invalid("", "invalid max parameter %q", param)
+	}
+	n, err := strconv.ParseFloat(value[0], 64)
+	if err != nil {
+		return Invalid("", "must be a number")
+	}
+	if n > max {
+		return Invalid("", "must be at most %s", param)
+	}
+	return nil
+}
+
+func validateLen(value []string, param string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return Invalid("", "invalid len parameter %q", param)
+	}
+	if len(value[0]) != n {
+		return Invalid("", "must be exactly %d characters", n)
+	}
+	return nil
+}
+
+func validateEmail(value []string, _ string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	if _, err := mail.ParseAddress(value[0]); err != nil {
+		return Invalid("", "must be a valid email address")
+	}
+	return nil
+}
+
+func validateURL(value []string, _ string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	u, err := url.ParseRequestURI(value[0])
+	if err != nil || u.Scheme == "" {
+		return Invalid("", "must be a valid URL")
+	}
+	return nil
+}
+
+func validateRegexp(value []string, param string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	re, err := regexp.Compile(param)
+	if err != nil {
+		return Invalid("", "invalid regexp parameter %q", param)
+	}
+	if !re.MatchString(value[0]) {
+		return Invalid("", "must match %s", param)
+	}
+	return nil
+}
+
+func validateOneof(value []string, param string) *ValidationError {
+	if empty(value) {
+		return nil
+	}
+	for _, opt := range strings.Fields(param) {
+		if opt == value[0] {
+			return nil
+		}
+	}
+	return Invalid("", "must be one of: %s", param)
+}
@@ -0,0 +1,171 @@
+package form
+
+import (
+	"net/url"
+	"testing"
+)
+
+type ValidatedPerson struct {
+	Age   int    `form:"age,required,min=0,max=150"`
+	Email string `form:"email,required,email"`
+}
+
+func TestUnmarshalTagValidators(t *testing.T) {
+	v := url.Values{}
+	v.Set("age", "200")
+	v.Set("email", "not-an-email")
+
+	p := &ValidatedPerson{}
+	err := Unmarshal(v, p)
+	if err == nil {
+		t.Fatal("Expected validation failure")
+	}
+
+	cve, ok := err.(CompoundValidationError)
+	if !ok {
+		t.Fatalf("Expected CompoundValidationError, got %T", err)
+	}
+	if cve.Len() != 2 {
+		t.Errorf("Expected 2 validation errors, got %d: %s", cve.Len(), cve.Error())
+	}
+}
+
+func TestUnmarshalTagValidatorsPass(t *testing.T) {
+	v := url.Values{}
+	v.Set("age", "42")
+	v.Set("email", "matt@example.com")
+
+	p := &ValidatedPerson{}
+	if err := Unmarshal(v, p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Age != 42 {
+		t.Errorf("Expected Age=42, got %d", p.Age)
+	}
+	if p.Email != "matt@example.com" {
+		t.Errorf("Expected Email=matt@example.com, got %q", p.Email)
+	}
+}
+
+func TestUnmarshalRequiredMissingKey(t *testing.T) {
+	type RequiredAge struct {
+		Age int `form:"age,required"`
+	}
+
+	a := &RequiredAge{}
+	err := Unmarshal(url.Values{}, a)
+	if err == nil {
+		t.Fatal("Expected a validation failure for a required field whose key is entirely absent")
+	}
+	if _, ok := err.(CompoundValidationError); !ok {
+		t.Fatalf("Expected CompoundValidationError, got %T", err)
+	}
+}
+
+type RequiredGroupAddress struct {
+	Zip string `form:"zip,required"`
+}
+
+type RequiredGroupForm struct {
+	Name    string                `form:"name,required"`
+	Mailing *RequiredGroupAddress `form:"+,omitempty,prefix=mail_"`
+}
+
+func TestUnmarshalRequiredMissingNestedGroupKey(t *testing.T) {
+	v := url.Values{}
+	v.Set("name", "Matt")
+
+	f := &RequiredGroupForm{}
+	err := Unmarshal(v, f)
+	if err == nil {
+		t.Fatal("Expected a validation failure for a required field nested inside a + group")
+	}
+
+	cve, ok := err.(CompoundValidationError)
+	if !ok {
+		t.Fatalf("Expected CompoundValidationError, got %T", err)
+	}
+
+	var found bool
+	for _, ve := range cve.Errors() {
+		if ve.Name == "mail_zip" {
+			found = true
+			if len(ve.Path) != 2 || ve.Path[0] != "Mailing" || ve.Path[1] != "Zip" {
+				t.Errorf("Expected Path=[Mailing Zip], got %v", ve.Path)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a validation error for mail_zip, got %s", cve.Error())
+	}
+}
+
+type SelfRefNode struct {
+	Value string       `form:"value,required"`
+	Next  *SelfRefNode `form:"+,omitempty,prefix=next_"`
+}
+
+func TestUnmarshalRequiredSelfReferentialGroupDoesNotRecurseForever(t *testing.T) {
+	v := url.Values{}
+	v.Set("value", "root")
+
+	n := &SelfRefNode{}
+	if err := Unmarshal(v, n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterValidator(t *testing.T) {
+	RegisterValidator("evenonly", func(value []string, _ string) *ValidationError {
+		if len(value[0])%2 != 0 {
+			return Invalid("", "must have an even length")
+		}
+		return nil
+	})
+
+	type OddCheck struct {
+		Code string `form:"code,evenonly"`
+	}
+
+	v := url.Values{}
+	v.Set("code", "odd")
+
+	oc := &OddCheck{}
+	if err := Unmarshal(v, oc); err == nil {
+		t.Fatal("Expected validation failure for odd-length code")
+	}
+}
+
+func TestRegisterAlias(t *testing.T) {
+	RegisterValidator("hexcolor", func(value []string, _ string) *ValidationError {
+		if value[0] != "#fff" {
+			return Invalid("", "not a hex color")
+		}
+		return nil
+	})
+	RegisterValidator("rgb", func(value []string, _ string) *ValidationError {
+		if value[0] != "rgb(0,0,0)" {
+			return Invalid("", "not an rgb color")
+		}
+		return nil
+	})
+	RegisterAlias("iscolor", "hexcolor|rgb")
+
+	type ColorForm struct {
+		Color string `form:"color,iscolor"`
+	}
+
+	v := url.Values{}
+	v.Set("color", "rgb(0,0,0)")
+
+	cf := &ColorForm{}
+	if err := Unmarshal(v, cf); err != nil {
+		t.Fatalf("Expected rgb(0,0,0) to satisfy iscolor alias: %s", err)
+	}
+
+	v.Set("color", "purple")
+	cf = &ColorForm{}
+	if err := Unmarshal(v, cf); err == nil {
+		t.Fatal("Expected purple to fail the iscolor alias")
+	}
+}